@@ -0,0 +1,21 @@
+//go:build brotli
+
+package compress
+
+import (
+	"io"
+
+	"github.com/andybalholm/brotli"
+)
+
+func init() {
+	Register(brotliEncoder{})
+}
+
+type brotliEncoder struct{}
+
+func (brotliEncoder) Name() string { return "br" }
+
+func (brotliEncoder) New(w io.Writer) (io.WriteCloser, error) {
+	return brotli.NewWriter(w), nil
+}
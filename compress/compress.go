@@ -0,0 +1,184 @@
+// Package compress negotiates a response encoding (gzip by default, brotli
+// and zstd when built with their tags) and transparently compresses
+// responses that are large enough and of an allowed content type.
+package compress
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/fyrna/mofu"
+)
+
+type Config struct {
+	// MinSize is the minimum response size, in bytes, before compression
+	// kicks in. Smaller bodies aren't worth the CPU.
+	// Default: 1024 (1KB)
+	MinSize int
+
+	// Types is the Content-Type prefix allowlist. A response is only
+	// compressed if its Content-Type starts with one of these.
+	// Default: text/*, application/json, application/javascript,
+	// application/xml, image/svg+xml
+	Types []string
+
+	// Preference breaks q-value ties between encoders the client accepts,
+	// in order from most to least preferred.
+	// Default: []string{"br", "zstd", "gzip"}
+	Preference []string
+}
+
+func Sparkle(opts ...ConfigOption) mofu.Middleware {
+	cfg := &Config{
+		MinSize: 1024,
+		Types: []string{
+			"text/",
+			"application/json",
+			"application/javascript",
+			"application/xml",
+			"image/svg+xml",
+		},
+		Preference: []string{"br", "zstd", "gzip"},
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return mofu.MwHug(func(c *mofu.C) error {
+		if c.Request.Header.Get("Accept-Encoding") == "" {
+			return c.Next()
+		}
+
+		cw := newWriter(c.Writer, c.Request, cfg)
+		c.Writer = cw
+
+		err := c.Next()
+
+		if cerr := cw.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+
+		return err
+	})
+}
+
+// writer buffers the first MinSize bytes of a response so it can decide
+// whether to compress without breaking streaming handlers: once the
+// decision is made it switches to a direct pass-through (optionally through
+// an Encoder) for the rest of the body.
+type writer struct {
+	http.ResponseWriter
+	req     *http.Request
+	cfg     *Config
+	buf     bytes.Buffer
+	status  int
+	decided bool
+	enc     io.WriteCloser
+}
+
+func newWriter(w http.ResponseWriter, r *http.Request, cfg *Config) *writer {
+	return &writer{ResponseWriter: w, req: r, cfg: cfg}
+}
+
+// Unwrap lets http.ResponseController (used by timeout.Sparkle's
+// isHeaderWritten check) see through to the real connection instead of
+// being fooled by our buffering.
+func (w *writer) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+func (w *writer) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *writer) Write(b []byte) (int, error) {
+	if w.decided {
+		return w.writeDirect(b)
+	}
+
+	w.buf.Write(b)
+
+	if w.buf.Len() >= w.cfg.MinSize {
+		if err := w.finalize(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+// finalize picks an encoder (or not), writes the status line and headers,
+// and flushes whatever was buffered so far.
+func (w *writer) finalize() error {
+	w.decided = true
+
+	ct := w.ResponseWriter.Header().Get("Content-Type")
+	if w.buf.Len() >= w.cfg.MinSize && typeAllowed(ct, w.cfg.Types) {
+		if enc := negotiate(w.req.Header.Get("Accept-Encoding"), w.cfg.Preference); enc != nil {
+			// Only commit to Content-Encoding once the encoder is actually
+			// built; falling back after the header is set would send a body
+			// the client can't decode as what we claimed.
+			if ew, err := enc.New(w.ResponseWriter); err == nil {
+				w.ResponseWriter.Header().Del("Content-Length")
+				w.ResponseWriter.Header().Set("Content-Encoding", enc.Name())
+				w.ResponseWriter.Header().Add("Vary", "Accept-Encoding")
+				w.enc = ew
+			}
+		}
+	}
+
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+
+	buffered := w.buf.Bytes()
+	w.buf = bytes.Buffer{}
+	_, err := w.writeDirect(buffered)
+	return err
+}
+
+func (w *writer) writeDirect(b []byte) (int, error) {
+	if w.enc != nil {
+		return w.enc.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Close finalizes a response that never reached MinSize and closes the
+// encoder, flushing any trailing compressed bytes. Call it once after the
+// handler chain returns.
+func (w *writer) Close() error {
+	if !w.decided {
+		if err := w.finalize(); err != nil {
+			return err
+		}
+	}
+
+	if w.enc != nil {
+		return w.enc.Close()
+	}
+
+	return nil
+}
+
+func typeAllowed(contentType string, allow []string) bool {
+	if contentType == "" {
+		return false
+	}
+
+	ct, _, _ := strings.Cut(contentType, ";")
+	ct = strings.TrimSpace(ct)
+
+	for _, prefix := range allow {
+		if strings.HasPrefix(ct, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriterPassesThroughSmallBody(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	cfg := &Config{MinSize: 1024, Types: []string{"text/"}, Preference: []string{"gzip"}}
+	w := newWriter(rec, req, cfg)
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("short body"))
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if enc := rec.Header().Get("Content-Encoding"); enc != "" {
+		t.Fatalf("expected no Content-Encoding for a body under MinSize, got %q", enc)
+	}
+	if rec.Body.String() != "short body" {
+		t.Fatalf("expected body passed through unchanged, got %q", rec.Body.String())
+	}
+}
+
+func TestWriterCompressesLargeAllowedBody(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	cfg := &Config{MinSize: 16, Types: []string{"text/"}, Preference: []string{"gzip"}}
+	w := newWriter(rec, req, cfg)
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+
+	body := strings.Repeat("a", 64)
+	w.Write([]byte(body))
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if enc := rec.Header().Get("Content-Encoding"); enc != "gzip" {
+		t.Fatalf("expected Content-Encoding: gzip, got %q", enc)
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(rec.Body.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gr.Close()
+
+	got, err := io.ReadAll(gr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != body {
+		t.Fatalf("decompressed body mismatch: got %q, want %q", got, body)
+	}
+}
+
+func TestWriterSkipsDisallowedContentType(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	cfg := &Config{MinSize: 4, Types: []string{"text/"}, Preference: []string{"gzip"}}
+	w := newWriter(rec, req, cfg)
+	w.Header().Set("Content-Type", "image/png")
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes.Repeat([]byte{0}, 64))
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if enc := rec.Header().Get("Content-Encoding"); enc != "" {
+		t.Fatalf("expected no compression for a disallowed content type, got %q", enc)
+	}
+}
@@ -0,0 +1,72 @@
+package compress
+
+import (
+	"strconv"
+	"strings"
+)
+
+// negotiate parses an Accept-Encoding header and returns the best registered
+// encoder, honoring q-values and falling back to preference order for ties.
+func negotiate(header string, preference []string) Encoder {
+	accepted := parseAcceptEncoding(header)
+
+	var best Encoder
+	bestQ := 0.0
+	bestRank := len(preference)
+
+	for name, q := range accepted {
+		if q <= 0 {
+			continue
+		}
+
+		enc, ok := registry[name]
+		if !ok {
+			continue
+		}
+
+		rank := indexOf(preference, name)
+		if rank == -1 {
+			rank = len(preference)
+		}
+
+		if q > bestQ || (q == bestQ && rank < bestRank) {
+			best, bestQ, bestRank = enc, q, rank
+		}
+	}
+
+	return best
+}
+
+func parseAcceptEncoding(header string) map[string]float64 {
+	out := map[string]float64{}
+
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, q := part, 1.0
+		if i := strings.IndexByte(part, ';'); i != -1 {
+			name = strings.TrimSpace(part[:i])
+			if qs, ok := strings.CutPrefix(strings.TrimSpace(part[i+1:]), "q="); ok {
+				if v, err := strconv.ParseFloat(qs, 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		out[name] = q
+	}
+
+	return out
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,53 @@
+package compress
+
+import "testing"
+
+func TestParseAcceptEncoding(t *testing.T) {
+	got := parseAcceptEncoding("gzip;q=0.8, br, zstd;q=0")
+	want := map[string]float64{"gzip": 0.8, "br": 1, "zstd": 0}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("got[%q] = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestNegotiateIgnoresUnregisteredEncoders(t *testing.T) {
+	// "identity" isn't a registered Encoder, so it can never be picked
+	// regardless of q-value; only gzip (registered by default) is eligible.
+	enc := negotiate("gzip;q=0.5, identity;q=1", []string{"gzip"})
+	if enc == nil || enc.Name() != "gzip" {
+		t.Fatalf("expected gzip, got %v", enc)
+	}
+}
+
+func TestNegotiateRejectsZeroQValue(t *testing.T) {
+	enc := negotiate("gzip;q=0", []string{"gzip"})
+	if enc != nil {
+		t.Fatalf("expected no encoder for q=0, got %v", enc)
+	}
+}
+
+func TestNegotiatePreferenceSkipsUnregisteredEncoders(t *testing.T) {
+	// Both accepted at q=1 (the default for a bare token) and "identity" is
+	// preferred, but only gzip is registered, so preference can't pick
+	// "identity".
+	enc := negotiate("gzip, identity", []string{"identity", "gzip"})
+	if enc == nil || enc.Name() != "gzip" {
+		t.Fatalf("expected gzip (only registered encoder), got %v", enc)
+	}
+}
+
+func TestIndexOf(t *testing.T) {
+	list := []string{"br", "zstd", "gzip"}
+	if i := indexOf(list, "gzip"); i != 2 {
+		t.Fatalf("indexOf(gzip) = %d, want 2", i)
+	}
+	if i := indexOf(list, "missing"); i != -1 {
+		t.Fatalf("indexOf(missing) = %d, want -1", i)
+	}
+}
@@ -0,0 +1,23 @@
+package compress
+
+// ConfigOption defines a function type for configuring the compress
+// middleware using the functional options pattern.
+type ConfigOption func(*Config)
+
+// SetMinSize returns a ConfigOption that sets the minimum response size, in
+// bytes, before compression kicks in.
+func SetMinSize(size int) ConfigOption {
+	return func(c *Config) { c.MinSize = size }
+}
+
+// SetTypes returns a ConfigOption that sets the Content-Type prefix
+// allowlist eligible for compression.
+func SetTypes(types ...string) ConfigOption {
+	return func(c *Config) { c.Types = types }
+}
+
+// SetPreference returns a ConfigOption that sets the encoder order used to
+// break q-value ties between encodings the client accepts.
+func SetPreference(names ...string) ConfigOption {
+	return func(c *Config) { c.Preference = names }
+}
@@ -0,0 +1,44 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+)
+
+// Encoder wraps a compression writer so the registry can pick between
+// gzip, brotli, zstd, etc. by their Accept-Encoding token.
+type Encoder interface {
+	// Name is the encoding token as it appears in Accept-Encoding /
+	// Content-Encoding, e.g. "gzip", "br", "zstd".
+	Name() string
+
+	// New wraps w with a writer that compresses into it. An error means the
+	// caller should skip compression for this response rather than having
+	// already committed to a Content-Encoding it can't produce.
+	New(w io.Writer) (io.WriteCloser, error)
+}
+
+var registry = map[string]Encoder{}
+
+// Register adds or replaces an encoder in the global registry. Optional
+// encoders (brotli.go, zstd.go) call this from an init() gated by a build
+// tag so they only compile in when the caller asks for them.
+func Register(e Encoder) {
+	registry[e.Name()] = e
+}
+
+func init() {
+	Register(gzipEncoder{level: gzip.DefaultCompression})
+}
+
+type gzipEncoder struct{ level int }
+
+func (gzipEncoder) Name() string { return "gzip" }
+
+func (e gzipEncoder) New(w io.Writer) (io.WriteCloser, error) {
+	gw, err := gzip.NewWriterLevel(w, e.level)
+	if err != nil {
+		gw = gzip.NewWriter(w)
+	}
+	return gw, nil
+}
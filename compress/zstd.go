@@ -0,0 +1,21 @@
+//go:build zstd
+
+package compress
+
+import (
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func init() {
+	Register(zstdEncoder{})
+}
+
+type zstdEncoder struct{}
+
+func (zstdEncoder) Name() string { return "zstd" }
+
+func (zstdEncoder) New(w io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(w)
+}
@@ -0,0 +1,145 @@
+// Package etag buffers small responses to compute a digest-based ETag and
+// answers conditional GETs with 304 Not Modified, cooperating with any
+// Cache-Control already set upstream (e.g. by static.Sparkle).
+package etag
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"hash/fnv"
+	"net/http"
+
+	"github.com/fyrna/mofu"
+)
+
+type Config struct {
+	// MaxBufferSize is the largest response body, in bytes, that will be
+	// buffered to compute an ETag. A response whose body grows past this
+	// limit streams straight through untouched, without an ETag.
+	// Default: 1<<20 (1MB)
+	MaxBufferSize int64
+
+	// Algorithm selects the digest used to derive the ETag: "fnv" (default,
+	// fast) or "sha1" (stronger, costlier).
+	// Default: "fnv"
+	Algorithm string
+}
+
+func Sparkle(opts ...ConfigOption) mofu.Middleware {
+	cfg := &Config{
+		MaxBufferSize: 1 << 20, // 1MB
+		Algorithm:     "fnv",
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return mofu.MwHug(func(c *mofu.C) error {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			return c.Next()
+		}
+
+		bw := &bufferingWriter{ResponseWriter: c.Writer, limit: cfg.MaxBufferSize}
+		c.Writer = bw
+
+		err := c.Next()
+
+		if ferr := bw.finalize(cfg, c.Request); ferr != nil && err == nil {
+			err = ferr
+		}
+
+		return err
+	})
+}
+
+// bufferingWriter buffers a response up to limit bytes so finalize can hash
+// it; responses that exceed the limit fall back to streaming unmodified.
+type bufferingWriter struct {
+	http.ResponseWriter
+	limit     int64
+	buf       bytes.Buffer
+	status    int
+	overLimit bool
+}
+
+// Unwrap lets http.ResponseController (used by timeout.Sparkle's
+// isHeaderWritten check) see through to the real connection instead of
+// being fooled by our buffering.
+func (w *bufferingWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+func (w *bufferingWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferingWriter) Write(b []byte) (int, error) {
+	if w.overLimit {
+		return w.ResponseWriter.Write(b)
+	}
+
+	if int64(w.buf.Len())+int64(len(b)) > w.limit {
+		w.overLimit = true
+		w.flushHeader()
+		if _, err := w.ResponseWriter.Write(w.buf.Bytes()); err != nil {
+			return 0, err
+		}
+		w.buf = bytes.Buffer{}
+		return w.ResponseWriter.Write(b)
+	}
+
+	return w.buf.Write(b)
+}
+
+func (w *bufferingWriter) flushHeader() {
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+}
+
+// finalize computes the ETag for a fully-buffered response, answers a
+// matching If-None-Match with 304, and otherwise flushes the response as
+// normal.
+func (w *bufferingWriter) finalize(cfg *Config, r *http.Request) error {
+	if w.overLimit {
+		return nil
+	}
+
+	if w.ResponseWriter.Header().Get("ETag") != "" {
+		// Someone downstream (e.g. static's fingerprinted assets) already
+		// set a strong ETag; don't recompute, just flush what's buffered.
+		w.flushHeader()
+		_, err := w.ResponseWriter.Write(w.buf.Bytes())
+		return err
+	}
+
+	body := w.buf.Bytes()
+	etag := computeETag(body, cfg.Algorithm)
+	w.ResponseWriter.Header().Set("ETag", etag)
+
+	if match := r.Header.Get("If-None-Match"); match != "" && match == etag {
+		w.ResponseWriter.Header().Del("Content-Length")
+		w.ResponseWriter.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	w.flushHeader()
+	_, err := w.ResponseWriter.Write(body)
+	return err
+}
+
+func computeETag(body []byte, algorithm string) string {
+	var sum []byte
+
+	switch algorithm {
+	case "sha1":
+		s := sha1.Sum(body)
+		sum = s[:]
+	default:
+		h := fnv.New64a()
+		h.Write(body)
+		sum = h.Sum(nil)
+	}
+
+	return `"` + hex.EncodeToString(sum) + `"`
+}
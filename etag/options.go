@@ -0,0 +1,17 @@
+package etag
+
+// ConfigOption defines a function type for configuring the etag middleware
+// using the functional options pattern.
+type ConfigOption func(*Config)
+
+// SetMaxBufferSize returns a ConfigOption that sets the largest response
+// body, in bytes, that will be buffered to compute an ETag.
+func SetMaxBufferSize(size int64) ConfigOption {
+	return func(c *Config) { c.MaxBufferSize = size }
+}
+
+// SetAlgorithm returns a ConfigOption that selects the digest algorithm used
+// to derive the ETag: "fnv" or "sha1".
+func SetAlgorithm(alg string) ConfigOption {
+	return func(c *Config) { c.Algorithm = alg }
+}
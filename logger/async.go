@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// AsyncHandler wraps a slog.Handler so request goroutines never block on log
+// I/O: Handle pushes the record onto a ring-buffer-backed channel and
+// returns immediately, while a background goroutine drains it into the
+// underlying handler. Loosely inspired by shengyanli1982/law.
+//
+// Records that arrive while the buffer is full are dropped rather than
+// blocking the caller; Dropped reports how many.
+type AsyncHandler struct {
+	next      slog.Handler
+	bufSize   int
+	ch        chan slog.Record
+	done      chan struct{}
+	closeOnce *sync.Once
+	dropped   *atomic.Uint64
+}
+
+// NewAsyncHandler starts the drain goroutine and returns the handler.
+// bufSize controls how many pending records the ring buffer can hold before
+// new records are dropped instead of blocking the request goroutine.
+func NewAsyncHandler(next slog.Handler, bufSize int) *AsyncHandler {
+	if bufSize <= 0 {
+		bufSize = 1024
+	}
+
+	h := &AsyncHandler{
+		next:      next,
+		bufSize:   bufSize,
+		ch:        make(chan slog.Record, bufSize),
+		done:      make(chan struct{}),
+		closeOnce: &sync.Once{},
+		dropped:   &atomic.Uint64{},
+	}
+
+	go h.drain()
+
+	return h
+}
+
+func (h *AsyncHandler) drain() {
+	defer close(h.done)
+	for r := range h.ch {
+		_ = h.next.Handle(context.Background(), r)
+	}
+}
+
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *AsyncHandler) Handle(_ context.Context, r slog.Record) (err error) {
+	// After Flush the channel is closed and a send panics; count the record
+	// as dropped instead of crashing the request goroutine.
+	defer func() {
+		if recover() != nil {
+			h.dropped.Add(1)
+		}
+	}()
+
+	select {
+	case h.ch <- r.Clone():
+	default:
+		h.dropped.Add(1)
+	}
+	return nil
+}
+
+// WithAttrs returns a handler bound to attrs. It gets its own channel, drain
+// goroutine and lifecycle over next.WithAttrs(attrs) rather than sharing h's:
+// sharing would leave the original drain goroutine handling every record
+// through the attr-less original next, silently losing whatever got bound.
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return NewAsyncHandler(h.next.WithAttrs(attrs), h.bufSize)
+}
+
+// WithGroup returns a handler scoped to name, for the same reason WithAttrs
+// doesn't share h's channel: see WithAttrs.
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	return NewAsyncHandler(h.next.WithGroup(name), h.bufSize)
+}
+
+// Dropped returns the number of records discarded because the buffer was
+// full or Flush had already been called.
+func (h *AsyncHandler) Dropped() uint64 {
+	return h.dropped.Load()
+}
+
+// Flush stops accepting new records, waits for the buffer to drain into the
+// underlying handler, and returns. Wire it to server shutdown so pending
+// logs aren't lost:
+//
+//	srv.RegisterOnShutdown(func() { handler.Flush(context.Background()) })
+//
+// Flush only drains this handler's own channel; a handler obtained via
+// WithAttrs/WithGroup must be flushed separately.
+func (h *AsyncHandler) Flush(ctx context.Context) error {
+	h.closeOnce.Do(func() { close(h.ch) })
+
+	select {
+	case <-h.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
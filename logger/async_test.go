@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestAsyncHandlerWithAttrsAppliesBoundAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.New(NewAsyncHandler(slog.NewJSONHandler(&buf, nil), 16))
+
+	derived := base.With("service", "checkout")
+	derived.Info("order placed")
+
+	flushLogger(t, derived)
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"service":"checkout"`)) {
+		t.Fatalf("expected bound attr \"service\":\"checkout\" in output, got: %s", buf.String())
+	}
+}
+
+func TestAsyncHandlerWithGroupAppliesGroup(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.New(NewAsyncHandler(slog.NewJSONHandler(&buf, nil), 16))
+
+	derived := base.WithGroup("req").With("method", "GET")
+	derived.Info("handled")
+
+	flushLogger(t, derived)
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"req":{"method":"GET"}`)) {
+		t.Fatalf("expected grouped attr under \"req\" in output, got: %s", buf.String())
+	}
+}
+
+func TestAsyncHandlerFlushWaitsForDrain(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewAsyncHandler(slog.NewJSONHandler(&buf, nil), 16)
+	logger := slog.New(h)
+
+	for i := 0; i < 10; i++ {
+		logger.Info("event")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := h.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if n := bytes.Count(buf.Bytes(), []byte(`"msg":"event"`)); n != 10 {
+		t.Fatalf("expected 10 drained records, got %d", n)
+	}
+}
+
+// flushLogger drains whichever *AsyncHandler backs l, failing the test if it
+// doesn't finish within the deadline.
+func flushLogger(t *testing.T, l *slog.Logger) {
+	t.Helper()
+	h, ok := l.Handler().(*AsyncHandler)
+	if !ok {
+		t.Fatalf("logger is not backed by an *AsyncHandler: %T", l.Handler())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := h.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fyrna/x/color"
+)
+
+// TextHandler is a slog.Handler that renders each record as the single-line,
+// ANSI-colored format mofu has always printed, e.g.:
+//
+//	[2006-01-02 15:04:05] 200 GET     /users | 127.0.0.1 | curl/8.0 | 1ms | 512B nyaa~
+type TextHandler struct {
+	w     io.Writer
+	mu    *sync.Mutex
+	color bool
+	attrs []slog.Attr
+}
+
+// NewTextHandler builds a TextHandler writing to w. Set enableColor to false
+// when w isn't a terminal (files, CI logs, …).
+func NewTextHandler(w io.Writer, enableColor bool) *TextHandler {
+	return &TextHandler{w: w, mu: &sync.Mutex{}, color: enableColor}
+}
+
+func (h *TextHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *TextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &TextHandler{w: h.w, mu: h.mu, color: h.color, attrs: append(cloneAttrs(h.attrs), attrs...)}
+}
+
+func (h *TextHandler) WithGroup(name string) slog.Handler {
+	// Groups aren't nested in the one-line format; attrs are flattened as-is.
+	return h
+}
+
+func (h *TextHandler) Handle(ctx context.Context, r slog.Record) error {
+	get := attrReader(h.attrs, r)
+
+	var sb strings.Builder
+	sb.Grow(200)
+
+	sb.WriteString(fmt.Sprintf("[%s] ", r.Time.Format("2006-01-02 15:04:05")))
+
+	status := int(get("status").Int64())
+	if h.color {
+		sb.WriteString(fmt.Sprintf("%s%3d%s ", getStatusColor(status), status, color.Reset))
+	} else {
+		sb.WriteString(fmt.Sprintf("%3d ", status))
+	}
+
+	method := fmt.Sprintf("%-7s", get("method").String())
+	if h.color {
+		sb.WriteString(fmt.Sprintf("%s%s%s ", color.Magenta, method, color.Reset))
+	} else {
+		sb.WriteString(fmt.Sprintf("%s ", method))
+	}
+
+	sb.WriteString(get("path").String())
+
+	if ip := get("ip").String(); ip != "" {
+		sb.WriteString(fmt.Sprintf(" | %s", ip))
+	}
+
+	if ua := get("ua").String(); ua != "" {
+		sb.WriteString(fmt.Sprintf(" | %s", truncate(ua, 50)))
+	}
+
+	durMS := get("duration_ms").Int64()
+	dur := time.Duration(durMS) * time.Millisecond
+	durationStr := fmt.Sprintf("%dms", durMS)
+	if h.color {
+		sb.WriteString(fmt.Sprintf(" | %s%s%s", getDurationColor(dur), durationStr, color.Reset))
+	} else {
+		sb.WriteString(fmt.Sprintf(" | %s", durationStr))
+	}
+
+	sb.WriteString(fmt.Sprintf(" | %dB", get("bytes").Int64()))
+
+	if reqID := get("request_id").String(); reqID != "" {
+		sb.WriteString(fmt.Sprintf(" | %s", reqID))
+	}
+
+	r.Attrs(func(a slog.Attr) bool {
+		switch a.Key {
+		case "req", "resp":
+			sb.WriteString(fmt.Sprintf(" | %s:%s", a.Key, truncate(groupBodyString(a.Value), 100))) //nolint:staticcheck
+		}
+		return true
+	})
+
+	sb.WriteString(" nyaa~\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, sb.String())
+	return err
+}
+
+// groupBodyString renders the "body" attr inside a req/resp slog.Group for
+// the one-line text format.
+func groupBodyString(v slog.Value) string {
+	for _, a := range v.Group() {
+		if a.Key != "body" {
+			continue
+		}
+		if raw, ok := a.Value.Any().(json.RawMessage); ok {
+			return string(raw)
+		}
+		return a.Value.String()
+	}
+	return ""
+}
+
+// attrReader returns a lookup over both the handler's bound attrs and the
+// record's own attrs, record attrs taking precedence.
+func attrReader(bound []slog.Attr, r slog.Record) func(key string) slog.Value {
+	m := make(map[string]slog.Value, len(bound)+r.NumAttrs())
+	for _, a := range bound {
+		m[a.Key] = a.Value
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		m[a.Key] = a.Value
+		return true
+	})
+	return func(key string) slog.Value { return m[key] }
+}
+
+func cloneAttrs(attrs []slog.Attr) []slog.Attr {
+	out := make([]slog.Attr, len(attrs))
+	copy(out, attrs)
+	return out
+}
+
+// NewJSONHandler builds the default JSON handler: one compact JSON object
+// per line, suitable for log aggregators.
+func NewJSONHandler(w io.Writer) slog.Handler {
+	return slog.NewJSONHandler(w, &slog.HandlerOptions{})
+}
+
+func getStatusColor(status int) string {
+	switch {
+	case status < 200:
+		return color.Cyan
+	case status < 300:
+		return color.Green
+	case status < 400:
+		return color.Yellow
+	case status < 500:
+		return color.Magenta
+	default:
+		return color.Red
+	}
+}
+
+func getDurationColor(d time.Duration) string {
+	switch {
+	case d < 100*time.Millisecond:
+		return color.Green
+	case d < 500*time.Millisecond:
+		return color.Yellow
+	default:
+		return color.Red
+	}
+}
+
+func isJSON(s string) bool {
+	if strings.TrimSpace(s) == "" {
+		return false
+	}
+	var js json.RawMessage
+	return json.Unmarshal([]byte(s), &js) == nil
+}
+
+func truncate(s string, length int) string {
+	if len(s) > length {
+		return s[:length] + "..."
+	}
+	return s
+}
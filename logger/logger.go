@@ -2,16 +2,17 @@ package logger
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"os"
 	"slices"
 	"strings"
 	"time"
 
 	"github.com/fyrna/mofu"
-	"github.com/fyrna/x/color"
 )
 
 type statusRecorder struct {
@@ -38,7 +39,7 @@ func (r *statusRecorder) Write(b []byte) (int, error) {
 }
 
 type Config struct {
-	// EnableColor enables colored output in logs to improve readability
+	// EnableColor enables colored output in the default text handler
 	// Default: true
 	EnableColor bool
 
@@ -71,9 +72,34 @@ type Config struct {
 	// If enabled, the client’s User-Agent will be shown in the logs
 	// Default: true
 	EnableUserAgent bool
+
+	// Handler is the slog.Handler records are emitted through. When nil,
+	// Sparkle builds a colored TextHandler wrapped in an AsyncHandler so the
+	// request goroutine never blocks on log I/O.
+	// Default: NewAsyncHandler(NewTextHandler(os.Stdout, EnableColor), 1024)
+	Handler slog.Handler
+
+	// Logger, when set, is used instead of building one from Handler.
+	// Prefer SetHandler unless the caller already owns a *slog.Logger
+	// (e.g. to share it with the rest of the app).
+	// Default: nil
+	Logger *slog.Logger
+}
+
+// Logger bundles the middleware returned by Sparkle with a Flush hook for
+// graceful shutdown, keeping Sparkle's own return a single value like every
+// other middleware constructor in this repo. Flush is always safe to call:
+// it's a no-op unless the effective logger is backed by an *AsyncHandler.
+//
+//	lg := logger.Sparkle()
+//	app.Use(lg.Middleware)
+//	srv.RegisterOnShutdown(func() { lg.Flush(context.Background()) })
+type Logger struct {
+	Middleware mofu.Middleware
+	Flush      func(ctx context.Context) error
 }
 
-func Sparkle(opts ...ConfigOption) mofu.Middleware {
+func Sparkle(opts ...ConfigOption) *Logger {
 	cfg := &Config{
 		EnableColor:     true,
 		LogRequestBody:  false,
@@ -92,7 +118,21 @@ func Sparkle(opts ...ConfigOption) mofu.Middleware {
 		cfg.MaxBodySize = 1024
 	}
 
-	return mofu.MwHug(func(c *mofu.C) error {
+	logger := cfg.Logger
+	if logger == nil {
+		handler := cfg.Handler
+		if handler == nil {
+			handler = NewAsyncHandler(NewTextHandler(os.Stdout, cfg.EnableColor), 1024)
+		}
+		logger = slog.New(handler)
+	}
+
+	flush := func(context.Context) error { return nil }
+	if async, ok := logger.Handler().(*AsyncHandler); ok {
+		flush = async.Flush
+	}
+
+	mw := mofu.MwHug(func(c *mofu.C) error {
 		// Skip logging for certain paths
 		if slices.Contains(cfg.SkipPaths, c.Request.URL.Path) {
 			return c.Next()
@@ -129,123 +169,61 @@ func Sparkle(opts ...ConfigOption) mofu.Middleware {
 		err := c.Next()
 		dur := time.Since(start)
 
-		// Format log entry
-		logEntry := formatLogEntry(c, recorder, dur, requestBody, recorder.body, cfg)
+		logRequest(logger, c, recorder, dur, requestBody, cfg)
 
-		fmt.Print(logEntry)
 		return err
 	})
-}
 
-func formatLogEntry(c *mofu.C, recorder *statusRecorder, dur time.Duration, reqBody []byte, respBody *bytes.Buffer, config *Config) string {
-	estSize := 200
-	if config.EnableIP {
-		estSize += 50
-	}
-	if config.EnableUserAgent {
-		estSize += 100
-	}
-
-	var sb strings.Builder
-	sb.Grow(estSize)
-
-	// Timestamp
-	sb.WriteString(fmt.Sprintf("[%s] ", time.Now().Format("2006-01-02 15:04:05")))
-
-	// Status code with color
-	if config.EnableColor {
-		sb.WriteString(fmt.Sprintf("%s%3d%s ",
-			getStatusColor(recorder.status), recorder.status, color.Reset))
-	} else {
-		sb.WriteString(fmt.Sprintf("%3d ", recorder.status))
-	}
+	return &Logger{Middleware: mw, Flush: flush}
+}
 
-	// Method
-	method := fmt.Sprintf("%-7s", c.Request.Method)
-	if config.EnableColor {
-		sb.WriteString(fmt.Sprintf("%s%s%s ", color.Magenta, method, color.Reset))
-	} else {
-		sb.WriteString(fmt.Sprintf("%s ", method))
+func logRequest(logger *slog.Logger, c *mofu.C, recorder *statusRecorder, dur time.Duration, reqBody []byte, cfg *Config) {
+	attrs := []slog.Attr{
+		slog.String("method", c.Request.Method),
+		slog.String("path", c.Request.URL.Path),
+		slog.Int("status", recorder.status),
+		slog.Int64("duration_ms", dur.Milliseconds()),
+		slog.Int("bytes", recorder.size),
 	}
 
-	// Path
-	sb.WriteString(c.Request.URL.Path)
-
-	// Query parameters
-	if c.Request.URL.RawQuery != "" {
-		sb.WriteString("?" + c.Request.URL.RawQuery)
+	if id, ok := c.Get("request_id"); ok {
+		if s, ok := id.(string); ok {
+			attrs = append(attrs, slog.String("request_id", s))
+		}
 	}
 
-	// Client IP
-	if config.EnableIP {
-		sb.WriteString(fmt.Sprintf(" | %s", getClientIP(c.Request)))
+	if cfg.EnableIP {
+		attrs = append(attrs, slog.String("ip", getClientIP(c.Request)))
 	}
 
-	// User Agent
-	if config.EnableUserAgent {
+	if cfg.EnableUserAgent {
 		if ua := c.Request.UserAgent(); ua != "" {
-			// Shorten long user agents
-			if len(ua) > 50 {
-				ua = ua[:47] + "..."
-			}
-			sb.WriteString(fmt.Sprintf(" | %s", ua))
+			attrs = append(attrs, slog.String("ua", ua))
 		}
 	}
 
-	// Duration with color based on performance
-	durationStr := formatDuration(dur)
-	if config.EnableColor {
-		sb.WriteString(fmt.Sprintf(" | %s%s%s",
-			getDurationColor(dur), durationStr, color.Reset))
-	} else {
-		sb.WriteString(fmt.Sprintf(" | %s", durationStr))
+	if cfg.LogRequestBody && len(reqBody) > 0 {
+		attrs = append(attrs, slog.Group("req", bodyAttr(reqBody)))
 	}
 
-	// Response size
-	sb.WriteString(fmt.Sprintf(" | %dB", recorder.size))
-
-	// Request body (if enabled)
-	if config.LogRequestBody && len(reqBody) > 0 {
-		bodyStr := string(reqBody)
-		if isJSON(bodyStr) {
-			sb.WriteString(" | req:")
-			sb.WriteString(truncate(string(reqBody), 100))
-		} else {
-			sb.WriteString(fmt.Sprintf(" | req:%q", truncate(string(reqBody), 100)))
+	if cfg.LogResponseBody && recorder.body != nil && recorder.body.Len() > 0 {
+		body := recorder.body.Bytes()
+		if len(body) > cfg.MaxBodySize {
+			body = body[:cfg.MaxBodySize]
 		}
+		attrs = append(attrs, slog.Group("resp", bodyAttr(body)))
 	}
 
-	// Response body (if enabled)
-	if config.LogResponseBody && respBody != nil && respBody.Len() > 0 {
-		body := respBody.Bytes()
-		if len(body) > config.MaxBodySize {
-			body = body[:config.MaxBodySize]
-		}
-
-		bodyStr := string(body)
-		if isJSON(bodyStr) {
-			sb.WriteString(" | resp:")
-			sb.WriteString(truncate(bodyStr, 100))
-		} else {
-			sb.WriteString(fmt.Sprintf(" | resp:%q", truncate(bodyStr, 100)))
-		}
-	}
-
-	sb.WriteString(" nyaa~\n")
-	return sb.String()
+	logger.LogAttrs(c.Request.Context(), slog.LevelInfo, "request handled", attrs...)
 }
 
-func formatDuration(d time.Duration) string {
-	switch {
-	case d < time.Microsecond:
-		return fmt.Sprintf("%dns", d.Nanoseconds())
-	case d < time.Millisecond:
-		return fmt.Sprintf("%.2fµs", float64(d.Microseconds()))
-	case d < time.Second:
-		return fmt.Sprintf("%.2fms", float64(d.Milliseconds()))
-	default:
-		return fmt.Sprintf("%.2fs", d.Seconds())
+// bodyAttr renders a captured body as a JSON value when it parses as JSON,
+// falling back to a plain string attr otherwise.
+func bodyAttr(body []byte) slog.Attr {
+	if isJSON(string(body)) {
+		return slog.Any("body", json.RawMessage(body))
 	}
+	return slog.String("body", string(body))
 }
 
 func getClientIP(r *http.Request) string {
@@ -258,44 +236,3 @@ func getClientIP(r *http.Request) string {
 	}
 	return strings.Split(r.RemoteAddr, ":")[0]
 }
-
-func getStatusColor(status int) string {
-	switch {
-	case status < 200:
-		return color.Cyan
-	case status < 300:
-		return color.Green
-	case status < 400:
-		return color.Yellow
-	case status < 500:
-		return color.Magenta
-	default:
-		return color.Red
-	}
-}
-
-func getDurationColor(d time.Duration) string {
-	switch {
-	case d < 100*time.Millisecond:
-		return color.Green
-	case d < 500*time.Millisecond:
-		return color.Yellow
-	default:
-		return color.Red
-	}
-}
-
-func isJSON(s string) bool {
-	if strings.TrimSpace(s) == "" {
-		return false
-	}
-	var js json.RawMessage
-	return json.Unmarshal([]byte(s), &js) == nil
-}
-
-func truncate(s string, length int) string {
-	if len(s) > length {
-		return s[:length] + "..."
-	}
-	return s
-}
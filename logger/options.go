@@ -1,5 +1,7 @@
 package logger
 
+import "log/slog"
+
 type ConfigOption func(*Config)
 
 func SetEnableColor(e bool) ConfigOption {
@@ -28,3 +30,18 @@ func SetEnableIP(e bool) ConfigOption {
 func SetEnableUserAgent(e bool) ConfigOption {
 	return func(c *Config) { c.EnableUserAgent = e }
 }
+
+// SetHandler overrides the slog.Handler records are emitted through.
+// The handler is used as given, without the default AsyncHandler wrapping;
+// wrap it in NewAsyncHandler yourself to keep log I/O off the request
+// goroutine.
+func SetHandler(h slog.Handler) ConfigOption {
+	return func(c *Config) { c.Handler = h }
+}
+
+// SetLogger overrides the *slog.Logger used directly, bypassing Handler.
+// Use this to share an existing logger (with its own attrs/groups) across
+// the app instead of letting Sparkle build one.
+func SetLogger(l *slog.Logger) ConfigOption {
+	return func(c *Config) { c.Logger = l }
+}
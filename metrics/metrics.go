@@ -0,0 +1,170 @@
+// Package metrics exposes RED-style HTTP metrics (rate, errors, duration)
+// for a mofu server via a user-supplied prometheus.Registerer.
+package metrics
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/fyrna/mofu"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+type Config struct {
+	// Registerer is where the metrics are registered.
+	// Default: prometheus.DefaultRegisterer
+	Registerer prometheus.Registerer
+
+	// Buckets are the histogram buckets (in seconds) for
+	// http_request_duration_seconds.
+	// Default: prometheus.DefBuckets
+	Buckets []float64
+
+	// PathLabelFunc derives the "path" label from the request context. It
+	// must return the route pattern ("/users/{id}") rather than the raw URL
+	// to avoid unbounded cardinality.
+	// Default: falls back to the raw URL path when the router doesn't
+	// expose a route pattern.
+	PathLabelFunc func(*mofu.C) string
+
+	// SkipPaths excludes paths from being metered, e.g. the metrics
+	// endpoint itself.
+	// Default: []string{"/metrics"}
+	SkipPaths []string
+}
+
+// RoutePattern is implemented by request contexts whose router attaches the
+// matched route template (e.g. "/users/{id}") instead of just the raw URL.
+// PathLabelFunc uses it when available, falling back to the raw URL path.
+type RoutePattern interface {
+	Pattern() string
+}
+
+func defaultPathLabel(c *mofu.C) string {
+	if rp, ok := any(c).(RoutePattern); ok {
+		return rp.Pattern()
+	}
+	return c.Request.URL.Path
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.size += n
+	return n, err
+}
+
+type collector struct {
+	requestsTotal   *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	requestSize     *prometheus.HistogramVec
+	responseSize    *prometheus.HistogramVec
+	inFlight        prometheus.Gauge
+}
+
+func newCollector(reg prometheus.Registerer, buckets []float64) *collector {
+	c := &collector{
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests processed.",
+		}, []string{"method", "path", "status"}),
+
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency in seconds.",
+			Buckets: buckets,
+		}, []string{"method", "path", "status"}),
+
+		requestSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_size_bytes",
+			Help:    "HTTP request body size in bytes.",
+			Buckets: prometheus.ExponentialBuckets(64, 4, 8),
+		}, []string{"method", "path"}),
+
+		responseSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_response_size_bytes",
+			Help:    "HTTP response body size in bytes.",
+			Buckets: prometheus.ExponentialBuckets(64, 4, 8),
+		}, []string{"method", "path", "status"}),
+
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "Number of HTTP requests currently being served.",
+		}),
+	}
+
+	reg.MustRegister(c.requestsTotal, c.requestDuration, c.requestSize, c.responseSize, c.inFlight)
+
+	return c
+}
+
+func Sparkle(opts ...ConfigOption) mofu.Middleware {
+	cfg := &Config{
+		Registerer:    prometheus.DefaultRegisterer,
+		Buckets:       prometheus.DefBuckets,
+		PathLabelFunc: defaultPathLabel,
+		SkipPaths:     []string{"/metrics"},
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	m := newCollector(cfg.Registerer, cfg.Buckets)
+
+	return mofu.MwHug(func(c *mofu.C) error {
+		if slices.Contains(cfg.SkipPaths, c.Request.URL.Path) {
+			return c.Next()
+		}
+
+		path := cfg.PathLabelFunc(c)
+		method := c.Request.Method
+
+		m.inFlight.Inc()
+		defer m.inFlight.Dec()
+
+		if c.Request.ContentLength > 0 {
+			m.requestSize.WithLabelValues(method, path).Observe(float64(c.Request.ContentLength))
+		}
+
+		recorder := &statusRecorder{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = recorder
+
+		start := time.Now()
+		err := c.Next()
+		dur := time.Since(start).Seconds()
+
+		status := strconv.Itoa(recorder.status)
+
+		m.requestsTotal.WithLabelValues(method, path, status).Inc()
+		m.requestDuration.WithLabelValues(method, path, status).Observe(dur)
+		m.responseSize.WithLabelValues(method, path, status).Observe(float64(recorder.size))
+
+		return err
+	})
+}
+
+// Handler returns an http.Handler serving the registered metrics. Mount it
+// directly with your router rather than behind Sparkle, so scraping
+// requests aren't recursively metered. Pass the same *prometheus.Registry
+// given to SetRegisterer (it implements Gatherer too) when using a custom
+// one; with no arguments it serves prometheus.DefaultGatherer.
+func Handler(gatherer ...prometheus.Gatherer) http.Handler {
+	if len(gatherer) == 0 {
+		return promhttp.Handler()
+	}
+	return promhttp.HandlerFor(gatherer[0], promhttp.HandlerOpts{})
+}
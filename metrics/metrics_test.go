@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fyrna/mofu"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// inFlightValue gathers reg and returns the current value of the
+// http_requests_in_flight gauge, so tests can assert Inc/Dec stay paired
+// without reaching into the collector Sparkle builds internally.
+func inFlightValue(t *testing.T, reg *prometheus.Registry) float64 {
+	t.Helper()
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range mfs {
+		if mf.GetName() == "http_requests_in_flight" {
+			return mf.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	t.Fatal("http_requests_in_flight not registered")
+	return 0
+}
+
+func TestDefaultPathLabelFallsBackToRawPath(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/orders/123", nil)
+	c := mofu.NewC(req, httptest.NewRecorder())
+
+	if got := defaultPathLabel(c); got != "/orders/123" {
+		t.Fatalf("defaultPathLabel = %q, want %q", got, "/orders/123")
+	}
+}
+
+func TestSparkleSkipsMeteringSkippedPaths(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	mw := Sparkle(SetRegisterer(reg), SetSkipPaths("/metrics"))
+
+	next := func(c *mofu.C) error { return c.Next() }
+	handler := mw(next)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	c := mofu.NewC(req, httptest.NewRecorder())
+
+	if err := handler(c); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+
+	if v := inFlightValue(t, reg); v != 0 {
+		t.Fatalf("in-flight gauge = %v after a skipped path, want 0 (never touched)", v)
+	}
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range mfs {
+		if mf.GetName() != "http_requests_total" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			if n := m.GetCounter().GetValue(); n != 0 {
+				t.Fatalf("requestsTotal counted a skipped path: %v", n)
+			}
+		}
+	}
+}
+
+func TestSparkleInFlightPairsOnHandlerError(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	mw := Sparkle(SetRegisterer(reg))
+
+	wantErr := errors.New("boom")
+	next := func(c *mofu.C) error { return wantErr }
+	handler := mw(next)
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	c := mofu.NewC(req, httptest.NewRecorder())
+
+	if err := handler(c); err != wantErr {
+		t.Fatalf("handler returned %v, want %v", err, wantErr)
+	}
+
+	if v := inFlightValue(t, reg); v != 0 {
+		t.Fatalf("in-flight gauge = %v after a failed request, want 0 (Inc/Dec should still pair)", v)
+	}
+}
+
+func TestSparkleTwiceOnSameRegistererPanics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	Sparkle(SetRegisterer(reg))
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected the second Sparkle() on the same Registerer to panic via MustRegister")
+		}
+	}()
+	Sparkle(SetRegisterer(reg))
+}
+
+func TestHandlerScrapesGatherer(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	Sparkle(SetRegisterer(reg))
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+
+	Handler(reg).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if rec.Body.Len() == 0 {
+		t.Fatal("expected a non-empty scrape body")
+	}
+}
@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"github.com/fyrna/mofu"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ConfigOption defines a function type for configuring the metrics
+// middleware using the functional options pattern.
+type ConfigOption func(*Config)
+
+// SetRegisterer returns a ConfigOption that sets where metrics are
+// registered instead of prometheus.DefaultRegisterer.
+func SetRegisterer(r prometheus.Registerer) ConfigOption {
+	return func(c *Config) { c.Registerer = r }
+}
+
+// SetBuckets returns a ConfigOption that sets the histogram buckets (in
+// seconds) for http_request_duration_seconds.
+func SetBuckets(buckets ...float64) ConfigOption {
+	return func(c *Config) { c.Buckets = buckets }
+}
+
+// SetPathLabelFunc returns a ConfigOption that overrides how the "path"
+// label is derived from the request context.
+func SetPathLabelFunc(fn func(*mofu.C) string) ConfigOption {
+	return func(c *Config) { c.PathLabelFunc = fn }
+}
+
+// SetSkipPaths returns a ConfigOption that excludes paths from being
+// metered.
+func SetSkipPaths(paths ...string) ConfigOption {
+	return func(c *Config) { c.SkipPaths = paths }
+}
@@ -0,0 +1,29 @@
+package recover
+
+// ConfigOption defines a function type for configuring the recover
+// middleware using the functional options pattern.
+type ConfigOption func(*Config)
+
+// SetStackSize returns a ConfigOption that sets the buffer size, in bytes,
+// used to capture a panic's stack trace.
+func SetStackSize(n int) ConfigOption {
+	return func(c *Config) { c.StackSize = n }
+}
+
+// SetPrintStack returns a ConfigOption that enables or disables writing the
+// recovered panic and its stack trace to stderr.
+func SetPrintStack(enable bool) ConfigOption {
+	return func(c *Config) { c.PrintStack = enable }
+}
+
+// SetSink returns a ConfigOption that sets the ErrorSink recovered panics
+// are reported to.
+func SetSink(sink ErrorSink) ConfigOption {
+	return func(c *Config) { c.Sink = sink }
+}
+
+// SetRenderer returns a ConfigOption that overrides how a recovered panic is
+// turned into a response.
+func SetRenderer(r Renderer) ConfigOption {
+	return func(c *Config) { c.Renderer = r }
+}
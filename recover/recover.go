@@ -0,0 +1,138 @@
+// Package recover turns panics inside handlers into a 500 response instead
+// of crashing the server, while still reporting them through a pluggable
+// ErrorSink (see the sentry sub-package for a Sentry-backed one).
+package recover
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/fyrna/mofu"
+)
+
+// ErrorSink receives recovered panics for out-of-band reporting (Sentry,
+// logs, a dead-letter queue, ...).
+type ErrorSink interface {
+	Capture(ctx context.Context, err error, r *http.Request)
+}
+
+type noopSink struct{}
+
+func (noopSink) Capture(context.Context, error, *http.Request) {}
+
+// Renderer converts a recovered panic into the response sent to the client.
+type Renderer func(c *mofu.C, err error) error
+
+func defaultRenderer(c *mofu.C, err error) error {
+	return c.String(http.StatusInternalServerError, "Internal Server Error")
+}
+
+// StackError wraps a recovered panic together with the stack trace captured
+// at the moment it was recovered, so an ErrorSink can report both.
+type StackError struct {
+	Err   error
+	Stack []byte
+}
+
+func (e *StackError) Error() string { return e.Err.Error() }
+func (e *StackError) Unwrap() error { return e.Err }
+
+// requestIDKey is the context key Sparkle stores the reqid middleware's
+// request ID under, for ErrorSink implementations to tag their reports with.
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the request ID Sparkle attached to ctx, if
+// the reqid middleware had set one on the request.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok
+}
+
+type Config struct {
+	// StackSize is the buffer size, in bytes, used to capture the panic's
+	// stack trace.
+	// Default: 4096
+	StackSize int
+
+	// PrintStack writes the recovered panic and its stack trace to stderr.
+	// Default: true
+	PrintStack bool
+
+	// Sink receives every recovered panic for reporting.
+	// Default: a no-op sink
+	Sink ErrorSink
+
+	// Renderer builds the response sent to the client after a panic.
+	// Default: 500 "Internal Server Error"
+	Renderer Renderer
+}
+
+func Sparkle(opts ...ConfigOption) mofu.Middleware {
+	cfg := &Config{
+		StackSize:  4096,
+		PrintStack: true,
+		Sink:       noopSink{},
+		Renderer:   defaultRenderer,
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if cfg.StackSize <= 0 {
+		cfg.StackSize = 4096
+	}
+
+	return mofu.MwHug(func(c *mofu.C) (err error) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+
+			stack := make([]byte, cfg.StackSize)
+			stack = stack[:runtime.Stack(stack, false)]
+
+			if cfg.PrintStack {
+				fmt.Fprintf(os.Stderr, "panic: %v\n%s", rec, stack)
+			}
+
+			panicErr, ok := rec.(error)
+			if !ok {
+				panicErr = fmt.Errorf("%v", rec)
+			}
+
+			ctx := c.Request.Context()
+			if id, ok := c.Get("request_id"); ok {
+				if s, ok := id.(string); ok {
+					ctx = context.WithValue(ctx, requestIDKey{}, s)
+				}
+			}
+
+			cfg.Sink.Capture(ctx, &StackError{Err: panicErr, Stack: stack}, c.Request)
+
+			if isHeaderWritten(c.Writer) {
+				// Something already wrote a response (most likely
+				// timeout.Sparkle's writer firing mid-handler); writing
+				// again would corrupt the stream, so just report and stop.
+				err = panicErr
+				return
+			}
+
+			err = cfg.Renderer(c, panicErr)
+		}()
+
+		return c.Next()
+	})
+}
+
+// isHeaderWritten mirrors the trick timeout.Sparkle uses: attempting to set
+// a dummy write deadline fails once headers are already on the wire.
+func isHeaderWritten(w http.ResponseWriter) bool {
+	rc := http.NewResponseController(w)
+	return rc.SetWriteDeadline(time.Now().Add(0)) != nil
+}
@@ -0,0 +1,165 @@
+package recover
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fyrna/mofu"
+)
+
+// fakeSink records every Capture call so tests can assert on what the
+// middleware reported without depending on a real ErrorSink backend.
+type fakeSink struct {
+	mu     sync.Mutex
+	called bool
+	err    error
+	reqID  string
+	hasID  bool
+}
+
+func (s *fakeSink) Capture(ctx context.Context, err error, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.called = true
+	s.err = err
+	s.reqID, s.hasID = RequestIDFromContext(ctx)
+}
+
+// fakeWriter implements http.ResponseWriter plus the SetWriteDeadline method
+// http.ResponseController looks for, so isHeaderWritten can be driven
+// deterministically regardless of whether a real connection happens to have
+// already flushed anything.
+type fakeWriter struct {
+	header      http.Header
+	body        []byte
+	status      int
+	wroteHeader bool
+}
+
+func (w *fakeWriter) Header() http.Header {
+	if w.header == nil {
+		w.header = http.Header{}
+	}
+	return w.header
+}
+
+func (w *fakeWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	w.body = append(w.body, b...)
+	return len(b), nil
+}
+
+func (w *fakeWriter) WriteHeader(code int) {
+	w.status = code
+	w.wroteHeader = true
+}
+
+func (w *fakeWriter) SetWriteDeadline(time.Time) error {
+	if w.wroteHeader {
+		return errors.New("headers already written")
+	}
+	return nil
+}
+
+func TestSparkleRecoversPanicAndReturns500(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := &fakeWriter{}
+
+	c := mofu.NewC(req, w)
+	c.Set("request_id", "req-123")
+
+	sink := &fakeSink{}
+	mw := Sparkle(SetSink(sink), SetPrintStack(false))
+
+	next := func(c *mofu.C) error { panic("boom") }
+	handler := mw(next)
+
+	if err := handler(c); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+
+	if w.status != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.status, http.StatusInternalServerError)
+	}
+	if string(w.body) != "Internal Server Error" {
+		t.Fatalf("body = %q, want %q", w.body, "Internal Server Error")
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	if !sink.called {
+		t.Fatal("expected Sink.Capture to be called")
+	}
+
+	se, ok := sink.err.(*StackError)
+	if !ok {
+		t.Fatalf("expected *StackError, got %T", sink.err)
+	}
+	if se.Err.Error() != "boom" {
+		t.Fatalf("StackError.Err = %q, want %q", se.Err.Error(), "boom")
+	}
+	if len(se.Stack) == 0 {
+		t.Fatal("expected a non-empty captured stack trace")
+	}
+
+	if !sink.hasID || sink.reqID != "req-123" {
+		t.Fatalf("request_id in Capture's context = (%q, %v), want (\"req-123\", true)", sink.reqID, sink.hasID)
+	}
+}
+
+func TestSparkleSkipsRendererWhenHeadersAlreadyWritten(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := &fakeWriter{}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("already sent"))
+
+	c := mofu.NewC(req, w)
+
+	sink := &fakeSink{}
+	mw := Sparkle(SetSink(sink), SetPrintStack(false))
+
+	next := func(c *mofu.C) error { panic("late panic") }
+	handler := mw(next)
+
+	err := handler(c)
+	if err == nil {
+		t.Fatal("expected the panic error to be returned when headers are already written")
+	}
+	if err.Error() != "late panic" {
+		t.Fatalf("err = %q, want %q", err.Error(), "late panic")
+	}
+
+	// The renderer must not have run a second write over the response
+	// timeout.Sparkle (or similar) already sent.
+	if string(w.body) != "already sent" {
+		t.Fatalf("body = %q, want the original response left untouched", w.body)
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if !sink.called {
+		t.Fatal("expected Sink.Capture to still be called even when the response was already written")
+	}
+}
+
+func TestSparkleClampsInvalidStackSize(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := &fakeWriter{}
+	c := mofu.NewC(req, w)
+
+	mw := Sparkle(SetStackSize(-1), SetPrintStack(false))
+	next := func(c *mofu.C) error { panic("boom") }
+
+	if err := mw(next)(c); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if w.status != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.status, http.StatusInternalServerError)
+	}
+}
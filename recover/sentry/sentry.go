@@ -0,0 +1,93 @@
+// Package sentry implements recover.ErrorSink via github.com/getsentry/sentry-go.
+package sentry
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/fyrna/mofu-mw/recover"
+	"github.com/getsentry/sentry-go"
+)
+
+// redactedHeaders lists the headers stripped from the request context sent
+// to Sentry by default, since they carry credentials or session state a
+// third-party service has no business seeing.
+var redactedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+	"X-Csrf-Token",
+}
+
+// Sink reports recovered panics to Sentry, tagging them with the reqid
+// middleware's request ID and attaching a sampled snapshot of the request
+// as extra context.
+type Sink struct {
+	// Hub is the Sentry hub to report through.
+	// Default: sentry.CurrentHub()
+	Hub *sentry.Hub
+
+	// SampleBody, when set, is called to attach a previously-sampled body
+	// (e.g. one already captured by logger or reproducer) as request
+	// context.
+	SampleBody func(*http.Request) []byte
+
+	// RedactHeaders lists additional headers (on top of the built-in
+	// Authorization/Cookie/Set-Cookie/... deny-list) to strip before
+	// attaching r.Header to Sentry's request context.
+	RedactHeaders []string
+}
+
+// NewSink builds a Sink reporting through the current Sentry hub.
+func NewSink() *Sink {
+	return &Sink{Hub: sentry.CurrentHub()}
+}
+
+// redactedHeaderSnapshot returns a copy of header with sensitive entries
+// (the built-in deny-list plus s.RedactHeaders) removed, so the caller never
+// forwards credentials or session cookies to Sentry.
+func (s *Sink) redactedHeaderSnapshot(header http.Header) http.Header {
+	out := header.Clone()
+	for _, k := range redactedHeaders {
+		out.Del(k)
+	}
+	for _, k := range s.RedactHeaders {
+		out.Del(k)
+	}
+	return out
+}
+
+func (s *Sink) Capture(ctx context.Context, err error, r *http.Request) {
+	hub := s.Hub
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+
+	hub.WithScope(func(scope *sentry.Scope) {
+		if id, ok := recover.RequestIDFromContext(ctx); ok {
+			scope.SetTag("request_id", id)
+		}
+
+		reqCtx := map[string]interface{}{
+			"method":  r.Method,
+			"url":     r.URL.String(),
+			"headers": s.redactedHeaderSnapshot(r.Header),
+		}
+		if s.SampleBody != nil {
+			if body := s.SampleBody(r); len(body) > 0 {
+				reqCtx["body"] = string(body)
+			}
+		}
+		scope.SetContext("request", reqCtx)
+
+		if se, ok := err.(*recover.StackError); ok {
+			scope.SetExtra("stack_trace", string(se.Stack))
+			hub.CaptureException(se.Err)
+			return
+		}
+
+		hub.CaptureException(err)
+	})
+}
@@ -0,0 +1,43 @@
+package sentry
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRedactedHeaderSnapshotStripsSensitiveHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer secret")
+	header.Set("Cookie", "session=abc123")
+	header.Set("X-Api-Key", "key")
+	header.Set("X-Request-Id", "req-1")
+
+	s := &Sink{}
+	redacted := s.redactedHeaderSnapshot(header)
+
+	for _, k := range []string{"Authorization", "Cookie", "X-Api-Key"} {
+		if redacted.Get(k) != "" {
+			t.Fatalf("expected %s to be redacted, got %q", k, redacted.Get(k))
+		}
+	}
+	if redacted.Get("X-Request-Id") != "req-1" {
+		t.Fatalf("expected non-sensitive header to survive, got %q", redacted.Get("X-Request-Id"))
+	}
+
+	// The original header must be untouched.
+	if header.Get("Authorization") == "" {
+		t.Fatal("redactedHeaderSnapshot mutated the original header")
+	}
+}
+
+func TestRedactedHeaderSnapshotHonorsCustomDenyList(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Internal-Token", "shh")
+
+	s := &Sink{RedactHeaders: []string{"X-Internal-Token"}}
+	redacted := s.redactedHeaderSnapshot(header)
+
+	if redacted.Get("X-Internal-Token") != "" {
+		t.Fatal("expected custom deny-list entry to be redacted")
+	}
+}
@@ -0,0 +1,34 @@
+package reproducer
+
+// ConfigOption defines a function type for configuring the reproducer
+// middleware using the functional options pattern.
+type ConfigOption func(*Config)
+
+// SetDir returns a ConfigOption that sets the directory artifacts are
+// written to.
+func SetDir(dir string) ConfigOption {
+	return func(c *Config) { c.Dir = dir }
+}
+
+// SetTriggerStatus returns a ConfigOption that sets the minimum response
+// status that triggers a capture.
+func SetTriggerStatus(status int) ConfigOption {
+	return func(c *Config) { c.TriggerStatus = status }
+}
+
+// SetSampleRate returns a ConfigOption that sets the fraction (0..1) of all
+// requests captured regardless of status.
+func SetSampleRate(rate float64) ConfigOption {
+	return func(c *Config) { c.SampleRate = rate }
+}
+
+// SetSpillThreshold returns a ConfigOption that sets how many body bytes may
+// accumulate in memory before spilling to a temp file.
+func SetSpillThreshold(bytes int64) ConfigOption {
+	return func(c *Config) { c.SpillThreshold = bytes }
+}
+
+// SetSkipPaths returns a ConfigOption that excludes paths from capture.
+func SetSkipPaths(paths ...string) ConfigOption {
+	return func(c *Config) { c.SkipPaths = paths }
+}
@@ -0,0 +1,40 @@
+package reproducer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// Replay reads the request half of a .http artifact produced by Sparkle and
+// re-sends it against target (a base URL such as "http://localhost:8080"),
+// returning whatever response the target gives back.
+func Replay(ctx context.Context, artifactPath string, target string) (*http.Response, error) {
+	data, err := os.ReadFile(artifactPath)
+	if err != nil {
+		return nil, fmt.Errorf("reproducer: read artifact: %w", err)
+	}
+
+	reqSection, _, _ := bytes.Cut(data, []byte(responseDelim))
+
+	req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(reqSection)))
+	if err != nil {
+		return nil, fmt.Errorf("reproducer: parse artifact request: %w", err)
+	}
+
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("reproducer: parse target: %w", err)
+	}
+
+	req.URL.Scheme = targetURL.Scheme
+	req.URL.Host = targetURL.Host
+	req.Host = targetURL.Host
+	req.RequestURI = ""
+
+	return http.DefaultClient.Do(req.WithContext(ctx))
+}
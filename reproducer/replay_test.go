@@ -0,0 +1,56 @@
+package reproducer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReplaySendsArtifactRequestToTarget(t *testing.T) {
+	var gotMethod, gotPath, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("replayed"))
+	}))
+	defer srv.Close()
+
+	artifact := "POST /widgets HTTP/1.1\r\n" +
+		"Host: original.example\r\n" +
+		"Content-Length: 11\r\n" +
+		"\r\n" +
+		"hello world" +
+		responseDelim +
+		"HTTP/1.1 500 Internal Server Error\r\n\r\n"
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte(artifact), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := Replay(context.Background(), path, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if gotMethod != http.MethodPost {
+		t.Fatalf("method = %q, want POST", gotMethod)
+	}
+	if gotPath != "/widgets" {
+		t.Fatalf("path = %q, want /widgets", gotPath)
+	}
+	if gotBody != "hello world" {
+		t.Fatalf("body = %q, want %q", gotBody, "hello world")
+	}
+	if resp.StatusCode != http.StatusTeapot {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusTeapot)
+	}
+}
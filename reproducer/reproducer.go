@@ -0,0 +1,219 @@
+// Package reproducer captures failing or sampled HTTP requests as
+// self-contained artifacts so operators can replay them later, following the
+// pattern used by FrostFS's request reproducer.
+package reproducer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/fyrna/mofu"
+)
+
+// responseDelim separates the request and response sections inside a
+// .http artifact.
+const responseDelim = "\r\n--- response ---\r\n"
+
+type Config struct {
+	// Dir is the directory artifacts are written to.
+	// Default: "./reproducer"
+	Dir string
+
+	// TriggerStatus captures any response whose status is this or higher.
+	// Default: 500
+	TriggerStatus int
+
+	// SampleRate additionally captures this fraction of all requests
+	// regardless of status, for baseline comparisons during post-mortems.
+	// Default: 0 (disabled)
+	SampleRate float64
+
+	// SpillThreshold is the number of body bytes allowed to accumulate in
+	// memory before they spill to a temp file. Keeps large uploads/downloads
+	// from blowing up process memory.
+	// Default: 1<<20 (1MB)
+	SpillThreshold int64
+
+	// SkipPaths excludes paths from capture entirely.
+	// Default: nil
+	SkipPaths []string
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	body   *spillBuffer
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	if n > 0 {
+		_, _ = r.body.Write(b[:n])
+	}
+	return n, err
+}
+
+type teeReadCloser struct {
+	r   io.ReadCloser
+	tee io.Writer
+}
+
+func (t *teeReadCloser) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	if n > 0 {
+		_, _ = t.tee.Write(p[:n])
+	}
+	return n, err
+}
+
+func (t *teeReadCloser) Close() error { return t.r.Close() }
+
+func Sparkle(opts ...ConfigOption) mofu.Middleware {
+	cfg := &Config{
+		Dir:            "./reproducer",
+		TriggerStatus:  http.StatusInternalServerError,
+		SpillThreshold: 1 << 20, // 1MB
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return mofu.MwHug(func(c *mofu.C) error {
+		if slices.Contains(cfg.SkipPaths, c.Request.URL.Path) {
+			return c.Next()
+		}
+
+		reqBody := newSpillBuffer(cfg.SpillThreshold)
+		if c.Request.Body != nil {
+			c.Request.Body = &teeReadCloser{r: c.Request.Body, tee: reqBody}
+		}
+
+		recorder := &statusRecorder{
+			ResponseWriter: c.Writer,
+			status:         http.StatusOK,
+			body:           newSpillBuffer(cfg.SpillThreshold),
+		}
+		c.Writer = recorder
+
+		start := time.Now()
+		err := c.Next()
+		dur := time.Since(start)
+
+		sampled := cfg.SampleRate > 0 && rand.Float64() < cfg.SampleRate
+		if recorder.status >= cfg.TriggerStatus || err != nil || sampled {
+			// Capture is best-effort and must never fail the request itself.
+			_ = cfg.capture(c, recorder, reqBody, dur, err)
+		}
+
+		_ = reqBody.Close()
+		_ = recorder.body.Close()
+
+		return err
+	})
+}
+
+type artifactMeta struct {
+	RequestID  string    `json:"request_id"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Status     int       `json:"status"`
+	DurationMS int64     `json:"duration_ms"`
+	Spilled    bool      `json:"spilled"`
+	Error      string    `json:"error,omitempty"`
+	CapturedAt time.Time `json:"captured_at"`
+}
+
+func (cfg *Config) capture(c *mofu.C, recorder *statusRecorder, reqBody *spillBuffer, dur time.Duration, handlerErr error) error {
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return err
+	}
+
+	reqID, _ := c.Get("request_id")
+	id, _ := reqID.(string)
+	if id == "" {
+		id = fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+
+	base := filepath.Join(cfg.Dir, id)
+
+	if err := writeHTTPArtifact(base+".http", c.Request, reqBody, recorder); err != nil {
+		return err
+	}
+
+	meta := artifactMeta{
+		RequestID:  id,
+		Method:     c.Request.Method,
+		Path:       c.Request.URL.Path,
+		Status:     recorder.status,
+		DurationMS: dur.Milliseconds(),
+		Spilled:    reqBody.Spilled() || recorder.body.Spilled(),
+		CapturedAt: time.Now().UTC(),
+	}
+	if handlerErr != nil {
+		meta.Error = handlerErr.Error()
+	}
+
+	f, err := os.Create(base + ".json")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(meta)
+}
+
+// writeHTTPArtifact renders the captured round trip as a raw HTTP request
+// followed by responseDelim and the raw HTTP response, so operators can read
+// it with a text editor or feed the request half to curl/Replay.
+func writeHTTPArtifact(path string, req *http.Request, reqBody *spillBuffer, resp *statusRecorder) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Fprintf(f, "%s %s %s\r\n", req.Method, req.URL.RequestURI(), req.Proto)
+	if err := req.Header.Write(f); err != nil {
+		return err
+	}
+	fmt.Fprint(f, "\r\n")
+
+	reqRC, err := reqBody.Reader()
+	if err != nil {
+		return err
+	}
+	defer reqRC.Close()
+	if _, err := io.Copy(f, reqRC); err != nil {
+		return err
+	}
+
+	fmt.Fprint(f, responseDelim)
+	fmt.Fprintf(f, "%s %d %s\r\n", req.Proto, resp.status, http.StatusText(resp.status))
+	if err := resp.Header().Write(f); err != nil {
+		return err
+	}
+	fmt.Fprint(f, "\r\n")
+
+	respRC, err := resp.body.Reader()
+	if err != nil {
+		return err
+	}
+	defer respRC.Close()
+	_, err = io.Copy(f, respRC)
+	return err
+}
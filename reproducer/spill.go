@@ -0,0 +1,72 @@
+package reproducer
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// spillBuffer accumulates written bytes in memory up to a threshold, then
+// transparently spills the rest to a temp file. It lets the middleware
+// capture request/response bodies of any size without holding large ones
+// entirely in memory.
+type spillBuffer struct {
+	threshold int64
+	buf       bytes.Buffer
+	file      *os.File
+}
+
+func newSpillBuffer(threshold int64) *spillBuffer {
+	if threshold <= 0 {
+		threshold = 1 << 20 // 1MB
+	}
+	return &spillBuffer{threshold: threshold}
+}
+
+func (s *spillBuffer) Write(p []byte) (int, error) {
+	if s.file != nil {
+		return s.file.Write(p)
+	}
+
+	if int64(s.buf.Len())+int64(len(p)) <= s.threshold {
+		return s.buf.Write(p)
+	}
+
+	f, err := os.CreateTemp("", "reproducer-*.body")
+	if err != nil {
+		// Fall back to unbounded in-memory buffering rather than losing data.
+		return s.buf.Write(p)
+	}
+	if _, err := f.Write(s.buf.Bytes()); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return s.buf.Write(p)
+	}
+	s.buf.Reset()
+	s.file = f
+	return s.file.Write(p)
+}
+
+// Reader returns a fresh reader over everything written so far.
+func (s *spillBuffer) Reader() (io.ReadCloser, error) {
+	if s.file == nil {
+		return io.NopCloser(bytes.NewReader(s.buf.Bytes())), nil
+	}
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(s.file), nil
+}
+
+// Spilled reports whether bytes were written to disk.
+func (s *spillBuffer) Spilled() bool { return s.file != nil }
+
+// Close releases the backing temp file, if any.
+func (s *spillBuffer) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	name := s.file.Name()
+	_ = s.file.Close()
+	return os.Remove(name)
+}
@@ -0,0 +1,92 @@
+package reproducer
+
+import (
+	"io"
+	"testing"
+)
+
+func TestSpillBufferStaysInMemoryUnderThreshold(t *testing.T) {
+	s := newSpillBuffer(1024)
+	if _, err := s.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Spilled() {
+		t.Fatal("expected buffer to stay in memory under threshold")
+	}
+
+	r, err := s.Reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello" {
+		t.Fatalf("got %q, want %q", got, "hello")
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSpillBufferSpillsToDiskOverThreshold(t *testing.T) {
+	s := newSpillBuffer(4)
+	if _, err := s.Write([]byte("this is longer than four bytes")); err != nil {
+		t.Fatal(err)
+	}
+
+	if !s.Spilled() {
+		t.Fatal("expected buffer to spill to disk once over threshold")
+	}
+
+	r, err := s.Reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "this is longer than four bytes" {
+		t.Fatalf("got %q, want the full written content", got)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reader after Close should fail since the temp file is gone.
+	if _, err := s.Reader(); err == nil {
+		t.Fatal("expected Reader to fail after Close removed the backing file")
+	}
+}
+
+func TestSpillBufferReaderIsRereadable(t *testing.T) {
+	s := newSpillBuffer(4)
+	if _, err := s.Write([]byte("spilled content")); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	for i := 0; i < 2; i++ {
+		r, err := s.Reader()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "spilled content" {
+			t.Fatalf("read %d: got %q, want %q", i, got, "spilled content")
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package static
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"regexp"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// fingerprintPattern matches "base.hash.ext", e.g. "app.abc12345.css".
+var fingerprintPattern = regexp.MustCompile(`^(.*)\.([0-9a-f]{8})(\.[^./]+)$`)
+
+// splitFingerprint strips the hash segment from a fingerprinted path,
+// returning the logical path it was derived from.
+func splitFingerprint(p string) (logical, hash string, ok bool) {
+	m := fingerprintPattern.FindStringSubmatch(p)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1] + m[3], m[2], true
+}
+
+// hashFile computes the short FNV-1a hash used to fingerprint a file's
+// contents.
+func hashFile(rootFS fs.FS, name string) (string, error) {
+	f, err := rootFS.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := fnv.New32a()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%08x", h.Sum32()), nil
+}
+
+// fingerprintIndex maps logical asset paths ("/app.css") to their
+// fingerprinted counterparts ("/app.abc12345.css") so AssetURL can emit
+// cache-busting URLs without re-hashing on every call.
+type fingerprintIndex struct {
+	mu      sync.RWMutex
+	toFP    map[string]string
+	rootFS  fs.FS
+	root    string
+	watcher *fsnotify.Watcher
+}
+
+func newFingerprintIndex(rootFS fs.FS, root string) *fingerprintIndex {
+	return &fingerprintIndex{toFP: map[string]string{}, rootFS: rootFS, root: root}
+}
+
+func (idx *fingerprintIndex) scan() error {
+	toFP := map[string]string{}
+
+	err := fs.WalkDir(idx.rootFS, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		hash, err := hashFile(idx.rootFS, p)
+		if err != nil {
+			return err
+		}
+
+		ext := path.Ext(p)
+		logical := "/" + p
+		fingerprinted := "/" + p[:len(p)-len(ext)] + "." + hash + ext
+
+		toFP[logical] = fingerprinted
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	idx.mu.Lock()
+	idx.toFP = toFP
+	idx.mu.Unlock()
+
+	return nil
+}
+
+// url returns the fingerprinted URL for a logical asset path, or the
+// logical path unchanged if it isn't known.
+func (idx *fingerprintIndex) url(logical string) string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if fp, ok := idx.toFP[logical]; ok {
+		return fp
+	}
+	return logical
+}
+
+// watch starts an fsnotify watcher over the real directory behind rootFS
+// (only possible when Config.FS is nil and os.DirFS is in use) and
+// rescans on any change. Errors are logged, not returned, since a failed
+// watch shouldn't prevent the middleware from serving assets.
+func (idx *fingerprintIndex) watch() {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("static: fingerprint watcher: %v", err)
+		return
+	}
+	idx.watcher = w
+
+	if err := walkDirs(idx.root, w.Add); err != nil {
+		log.Printf("static: fingerprint watcher: %v", err)
+	}
+
+	go func() {
+		for {
+			select {
+			case _, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if err := idx.scan(); err != nil {
+					log.Printf("static: fingerprint rescan: %v", err)
+				}
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("static: fingerprint watcher: %v", err)
+			}
+		}
+	}()
+}
+
+// close stops the fsnotify watcher started by watch, if any, releasing its
+// inotify fd and letting its event-handling goroutine exit. Safe to call
+// even when watch was never started.
+func (idx *fingerprintIndex) close() error {
+	if idx.watcher == nil {
+		return nil
+	}
+	return idx.watcher.Close()
+}
+
+// walkDirs registers every directory under root (including root
+// itself) with add, since fsnotify only watches the directories you give it.
+func walkDirs(root string, add func(string) error) error {
+	return fs.WalkDir(os.DirFS(root), ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		return add(path.Join(root, p))
+	})
+}
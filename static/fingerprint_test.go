@@ -0,0 +1,73 @@
+package static
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFingerprintIndexScopedToSubRoot(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(dir, "dist"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "dist", "app.css"), []byte("body{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rootFS := os.DirFS(dir)
+
+	// Mimic what Sparkle does with SetRoot("dist"): the index must be scanned
+	// relative to the sub-root, not the whole rootFS, so its logical keys
+	// ("/app.css") match what request-time lookups join cfg.Root onto.
+	scanFS, err := fs.Sub(rootFS, "dist")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx := newFingerprintIndex(scanFS, filepath.Join(dir, "dist"))
+	if err := idx.scan(); err != nil {
+		t.Fatal(err)
+	}
+
+	fingerprinted := idx.url("/app.css")
+	if fingerprinted == "/app.css" {
+		t.Fatal("expected fingerprinted URL, got logical path unchanged (index not scoped to sub-root)")
+	}
+
+	logicalRel, hash, ok := splitFingerprint(fingerprinted)
+	if !ok {
+		t.Fatalf("fingerprinted URL %q doesn't match the expected pattern", fingerprinted)
+	}
+	if logicalRel != "/app.css" {
+		t.Fatalf("expected logical path /app.css, got %q", logicalRel)
+	}
+
+	// The request handler re-joins cfg.Root ("dist") onto the logical path
+	// before hashing against the unscoped rootFS; the hash must still match.
+	actualHash, err := hashFile(rootFS, filepath.Join("dist", logicalRel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actualHash != hash {
+		t.Fatalf("hash mismatch: fingerprint has %q, file hashes to %q", hash, actualHash)
+	}
+}
+
+func TestFingerprintIndexUnscopedLogicalPathUnaffectedByRoot(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.css"), []byte("body{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	idx := newFingerprintIndex(os.DirFS(dir), dir)
+	if err := idx.scan(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := idx.url("/app.css"); got == "/app.css" {
+		t.Fatal("expected fingerprinted URL for a known asset at root \".\"")
+	}
+}
@@ -41,3 +41,22 @@ func SetFS(fs fs.FS) ConfigOption {
 func SetCacheAge(a int) ConfigOption {
 	return func(c *Config) { c.CacheAge = a }
 }
+
+// SetFingerprint returns a ConfigOption that enables content-hash
+// fingerprinted asset URLs and the AssetURL helper.
+func SetFingerprint(enable bool) ConfigOption {
+	return func(c *Config) { c.Fingerprint = enable }
+}
+
+// SetImmutableMaxAge returns a ConfigOption that sets the max-age in seconds
+// for fingerprinted assets' Cache-Control header.
+func SetImmutableMaxAge(seconds int) ConfigOption {
+	return func(c *Config) { c.ImmutableMaxAge = seconds }
+}
+
+// SetWatch returns a ConfigOption that enables an fsnotify-based watcher
+// rescanning the fingerprint index on file change. Only takes effect when
+// using the default os.DirFS (i.e. SetFS was not called).
+func SetWatch(enable bool) ConfigOption {
+	return func(c *Config) { c.Watch = enable }
+}
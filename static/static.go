@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"log"
 	"net/http"
 	"os"
 	"path"
@@ -45,14 +46,46 @@ type Config struct {
 	// filesystem. When nil, os.DirFS is used with the provided root.
 	// Default: nil
 	FS fs.FS
+
+	// Fingerprint enables content-hash fingerprinted asset URLs
+	// (e.g. "/app.abc12345.css"). When enabled, AssetURL becomes available
+	// on *mofu.C and fingerprinted requests are served as immutable.
+	// Default: false
+	Fingerprint bool
+
+	// ImmutableMaxAge sets the max-age value in seconds for the
+	// Cache-Control header of fingerprinted assets.
+	// Default: 31536000 (1 year)
+	ImmutableMaxAge int
+
+	// Watch enables an fsnotify-based watcher that rescans the fingerprint
+	// index on file change. Only takes effect when FS is nil (os.DirFS).
+	// Default: false
+	Watch bool
 }
 
-func Sparkle(root string, opts ...ConfigOption) mofu.Middleware {
+// Static bundles the middleware returned by Sparkle with a Close method that
+// stops the fingerprint watcher started by SetWatch(true), if any. Wire it
+// to server shutdown the same way logger.Logger.Flush is, so the watcher's
+// inotify fd and goroutine don't outlive the server:
+//
+//	st := static.Sparkle("dist", static.SetFingerprint(true), static.SetWatch(true))
+//	app.Use(st.Middleware)
+//	srv.RegisterOnShutdown(func() { st.Close() })
+//
+// Close is always safe to call, even when SetWatch was never set.
+type Static struct {
+	Middleware mofu.Middleware
+	Close      func() error
+}
+
+func Sparkle(root string, opts ...ConfigOption) *Static {
 	cfg := &Config{
-		Index:    "index.html",
-		Prefix:   "",
-		CacheAge: 3600,
-		Root:     ".",
+		Index:           "index.html",
+		Prefix:          "",
+		CacheAge:        3600,
+		Root:            ".",
+		ImmutableMaxAge: 31536000,
 	}
 
 	for _, opt := range opts {
@@ -67,11 +100,41 @@ func Sparkle(root string, opts ...ConfigOption) mofu.Middleware {
 		rootFS = os.DirFS(root)
 	}
 
-	return mofu.MwHug(func(c *mofu.C) error {
+	var fpIndex *fingerprintIndex
+	if cfg.Fingerprint {
+		scanFS := rootFS
+		if cfg.Root != "." && cfg.Root != "" {
+			sub, err := fs.Sub(rootFS, cfg.Root)
+			if err != nil {
+				log.Printf("static: fingerprint scan: %v", err)
+			} else {
+				scanFS = sub
+			}
+		}
+
+		fpIndex = newFingerprintIndex(scanFS, path.Join(root, cfg.Root))
+		if err := fpIndex.scan(); err != nil {
+			log.Printf("static: fingerprint scan: %v", err)
+		}
+		if cfg.Watch && cfg.FS == nil {
+			fpIndex.watch()
+		}
+	}
+
+	closeFn := func() error { return nil }
+	if fpIndex != nil {
+		closeFn = fpIndex.close
+	}
+
+	mw := mofu.MwHug(func(c *mofu.C) error {
 		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
 			return c.Next()
 		}
 
+		if fpIndex != nil {
+			c.Set("AssetURL", fpIndex.url)
+		}
+
 		urlPath := c.Request.URL.Path
 
 		if cfg.Prefix != "" {
@@ -92,6 +155,29 @@ func Sparkle(root string, opts ...ConfigOption) mofu.Middleware {
 			urlPath = "."
 		}
 
+		if cfg.Fingerprint {
+			if logicalRel, hash, ok := splitFingerprint(urlPath); ok {
+				filePath := path.Join(cfg.Root, logicalRel)
+
+				actualHash, err := hashFile(rootFS, filePath)
+				if err != nil || actualHash != hash {
+					c.Writer.WriteHeader(http.StatusNotFound)
+					c.Abort()
+					return nil
+				}
+
+				file, err := rootFS.Open(filePath)
+				if err != nil {
+					c.Writer.WriteHeader(http.StatusNotFound)
+					c.Abort()
+					return nil
+				}
+				defer file.Close()
+
+				return serveImmutableFile(c, file, filePath, hash, cfg.ImmutableMaxAge)
+			}
+		}
+
 		filePath := path.Join(cfg.Root, urlPath)
 		file, err := rootFS.Open(filePath)
 		if err != nil {
@@ -132,6 +218,8 @@ func Sparkle(root string, opts ...ConfigOption) mofu.Middleware {
 
 		return serveFile(c, file, stat.Name(), cfg.CacheAge)
 	})
+
+	return &Static{Middleware: mw, Close: closeFn}
 }
 
 func serveFile(c *mofu.C, file fs.File, name string, maxAge int) error {
@@ -149,6 +237,25 @@ func serveFile(c *mofu.C, file fs.File, name string, maxAge int) error {
 	return nil
 }
 
+// serveImmutableFile serves a fingerprinted asset with a strong ETag and a
+// far-future, immutable Cache-Control header since its URL changes whenever
+// its content does.
+func serveImmutableFile(c *mofu.C, file fs.File, name, hash string, maxAge int) error {
+	stat, err := file.Stat()
+	if err != nil {
+		return c.Next()
+	}
+
+	c.SetHeader("Content-Type", detectContentType(name))
+	c.SetHeader("Cache-Control", fmt.Sprintf("public, max-age=%d, immutable", maxAge))
+	c.SetHeader("ETag", fmt.Sprintf("%q", hash))
+	c.SetHeader("X-Content-Type-Options", "nosniff")
+
+	http.ServeContent(c.Writer, c.Request, name, stat.ModTime(), file.(io.ReadSeeker))
+	c.Abort()
+	return nil
+}
+
 var contentTypes = map[string]string{
 	".css":  "text/css; charset=utf-8",
 	".js":   "application/javascript; charset=utf-8",